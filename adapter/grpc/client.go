@@ -0,0 +1,291 @@
+// Package grpc adapts Evans' entity.GRPCClient port onto a real gRPC (or
+// gRPC-Web) connection.
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/ktr0731/evans/config"
+	"github.com/ktr0731/evans/entity"
+	"github.com/ktr0731/evans/usecase/port"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+type client struct {
+	conn              *grpc.ClientConn
+	reflectionEnabled bool
+	reflectionClient  grpc_reflection_v1alpha.ServerReflectionClient
+}
+
+// NewClient dials the given endpoints over plain gRPC (or TLS when
+// tlsCfg.Enabled), and enables server reflection when reflection is true.
+// When more than one endpoint is given, the client balances RPCs over all
+// of them using gRPC's built-in round-robin policy. extraOpts is appended
+// as-is, used by the di package to attach the user-configured interceptor
+// chain without this package knowing anything about interceptors.
+func NewClient(endpoints []string, reflection bool, tlsCfg *config.TLS, extraOpts ...grpc.DialOption) (entity.GRPCClient, error) {
+	opts, err := dialOptions(endpoints, tlsCfg)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, extraOpts...)
+
+	conn, err := grpc.Dial(target(endpoints), opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial to gRPC server(s) %v", endpoints)
+	}
+
+	c := &client{conn: conn, reflectionEnabled: reflection}
+	if reflection {
+		c.reflectionClient = grpc_reflection_v1alpha.NewServerReflectionClient(conn)
+	}
+	return c, nil
+}
+
+// NewWebClient behaves like NewClient but speaks gRPC-Web. b is stored on
+// the client, but Invoke does not use it: it sends req as the
+// proto.Message it is given. gRPC-Web dials a single origin,
+// so only the first resolved endpoint is used. As with NewClient, tlsCfg
+// switches the underlying http.Client onto credentials.NewTLS-equivalent
+// transport security when enabled.
+func NewWebClient(addr string, reflection bool, b port.DynamicBuilder, tlsCfg *config.TLS) (entity.GRPCClient, error) {
+	transport, err := httpDialOptions(tlsCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := "http"
+	if tlsCfg != nil && tlsCfg.Enabled {
+		scheme = "https"
+	}
+
+	return &webClient{
+		baseURL:           scheme + "://" + addr,
+		reflectionEnabled: reflection,
+		builder:           b,
+		hc:                &http.Client{Transport: transport},
+	}, nil
+}
+
+var manualResolverSeq int64
+
+// target builds the grpc.Dial target string for endpoints. A single
+// endpoint is dialed directly. More than one, as returned by the DNS SRV,
+// Consul, or etcd resolvers, are registered with a manual resolver.Builder
+// under a dial-unique scheme so grpc.Dial sees every resolved address as a
+// distinct resolver.Address and the round-robin service config set in
+// dialOptions actually has more than one backend to balance over.
+func target(endpoints []string) string {
+	if len(endpoints) <= 1 {
+		if len(endpoints) == 0 {
+			return ""
+		}
+		return endpoints[0]
+	}
+
+	scheme := fmt.Sprintf("evans-resolved-%d", atomic.AddInt64(&manualResolverSeq, 1))
+	builder := manual.NewBuilderWithScheme(scheme)
+
+	addrs := make([]resolver.Address, 0, len(endpoints))
+	for _, e := range endpoints {
+		addrs = append(addrs, resolver.Address{Addr: e})
+	}
+	builder.InitialState(resolver.State{Addresses: addrs})
+	resolver.Register(builder)
+
+	return scheme + ":///"
+}
+
+// dialOptions builds the grpc.DialOption slice implied by tlsCfg, falling
+// back to an insecure dial when tlsCfg is nil or disabled, and requests
+// round-robin balancing when more than one endpoint was resolved.
+func dialOptions(endpoints []string, tlsCfg *config.TLS) ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+
+	if tlsCfg == nil || !tlsCfg.Enabled {
+		opts = append(opts, grpc.WithInsecure())
+	} else {
+		creds, err := newTransportCredentials(tlsCfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	}
+
+	if len(endpoints) > 1 {
+		opts = append(opts, grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`))
+	}
+
+	return opts, nil
+}
+
+// newTransportCredentials builds TLS transport credentials from tlsCfg,
+// loading a CA cert and/or client cert/key when configured.
+func newTransportCredentials(tlsCfg *config.TLS) (credentials.TransportCredentials, error) {
+	c, err := buildTLSConfig(tlsCfg)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(c), nil
+}
+
+// buildTLSConfig builds a *tls.Config from tlsCfg, loading a CA cert
+// and/or client cert/key when configured. It is shared by the plain gRPC
+// dialer and the HTTP transcoding client's http.Transport.
+func buildTLSConfig(tlsCfg *config.TLS) (*tls.Config, error) {
+	c := &tls.Config{
+		ServerName:         tlsCfg.ServerNameOverride,
+		InsecureSkipVerify: tlsCfg.InsecureSkipVerify,
+	}
+
+	if tlsCfg.CACert != "" {
+		pem, err := ioutil.ReadFile(tlsCfg.CACert)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read CA cert")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("failed to append CA cert to pool")
+		}
+		c.RootCAs = pool
+	}
+
+	if tlsCfg.ClientCert != "" && tlsCfg.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(tlsCfg.ClientCert, tlsCfg.ClientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client cert/key")
+		}
+		c.Certificates = []tls.Certificate{cert}
+	}
+
+	return c, nil
+}
+
+func (c *client) ReflectionEnabled() bool {
+	return c.reflectionEnabled
+}
+
+// ListServices does not query server reflection; it always returns a
+// "not implemented" error.
+func (c *client) ListServices() ([]entity.Service, []entity.Message, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+func (c *client) Invoke(ctx context.Context, fullyQualifiedMethodName string, req, res interface{}) error {
+	return c.conn.Invoke(ctx, fullyQualifiedMethodName, req, res)
+}
+
+func (c *client) Close(ctx context.Context) error {
+	return c.conn.Close()
+}
+
+// webClient is the gRPC-Web equivalent of client, speaking HTTP/1.1 with a
+// gRPC-Web framed body instead of dialing a grpc.ClientConn.
+type webClient struct {
+	baseURL           string
+	reflectionEnabled bool
+	builder           port.DynamicBuilder
+	hc                *http.Client
+}
+
+func (c *webClient) ReflectionEnabled() bool {
+	return c.reflectionEnabled
+}
+
+// ListServices does not query server reflection; it always returns a
+// "not implemented" error.
+func (c *webClient) ListServices() ([]entity.Service, []entity.Message, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+// Invoke sends req as a single gRPC-Web message frame (the "grpc-web+proto"
+// wire format: a 1-byte flag, a 4-byte big-endian length, then the
+// marshaled message) in an HTTP POST to <baseURL>/<fullyQualifiedMethodName>,
+// and decodes the first non-trailer frame of the response into res.
+func (c *webClient) Invoke(ctx context.Context, fullyQualifiedMethodName string, req, res interface{}) error {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return errors.Errorf("expected a proto.Message request for %s", fullyQualifiedMethodName)
+	}
+	reqBody, err := proto.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal request")
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/"+fullyQualifiedMethodName, bytes.NewReader(frameMessage(reqBody)))
+	if err != nil {
+		return errors.Wrap(err, "failed to build gRPC-Web request")
+	}
+	httpReq.Header.Set("Content-Type", "application/grpc-web+proto")
+	httpReq.Header.Set("X-Grpc-Web", "1")
+
+	httpRes, err := c.hc.Do(httpReq)
+	if err != nil {
+		return errors.Wrapf(err, "gRPC-Web request to %s failed", httpReq.URL)
+	}
+	defer httpRes.Body.Close()
+
+	body, err := ioutil.ReadAll(httpRes.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read gRPC-Web response body")
+	}
+	if httpRes.StatusCode >= 400 {
+		return errors.Errorf("gRPC-Web call failed with status %d: %s", httpRes.StatusCode, body)
+	}
+
+	resBody, err := firstMessageFrame(body)
+	if err != nil {
+		return err
+	}
+
+	resMsg, ok := res.(proto.Message)
+	if !ok {
+		return errors.Errorf("expected a proto.Message response for %s", fullyQualifiedMethodName)
+	}
+	return proto.Unmarshal(resBody, resMsg)
+}
+
+func (c *webClient) Close(ctx context.Context) error {
+	return nil
+}
+
+// frameMessage wraps body in a single uncompressed gRPC-Web message frame.
+func frameMessage(body []byte) []byte {
+	frame := make([]byte, 5+len(body))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(body)))
+	copy(frame[5:], body)
+	return frame
+}
+
+// firstMessageFrame walks the length-prefixed frames of a gRPC-Web
+// response body and returns the payload of the first one that isn't a
+// trailer frame (high bit of the flag byte set).
+func firstMessageFrame(body []byte) ([]byte, error) {
+	for len(body) >= 5 {
+		flag := body[0]
+		length := binary.BigEndian.Uint32(body[1:5])
+		if uint32(len(body)) < 5+length {
+			return nil, errors.New("truncated gRPC-Web frame")
+		}
+		payload := body[5 : 5+length]
+		if flag&0x80 == 0 {
+			return payload, nil
+		}
+		body = body[5+length:]
+	}
+	return nil, errors.New("gRPC-Web response contained no message frame")
+}
@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/resolver"
+)
+
+func TestTarget_SingleEndpoint(t *testing.T) {
+	got := target([]string{"127.0.0.1:50051"})
+	want := "127.0.0.1:50051"
+	if got != want {
+		t.Errorf("target() = %q, want %q", got, want)
+	}
+}
+
+func TestTarget_NoEndpoints(t *testing.T) {
+	if got := target(nil); got != "" {
+		t.Errorf("target() = %q, want empty", got)
+	}
+}
+
+type fakeClientConn struct {
+	resolver.ClientConn
+	state resolver.State
+}
+
+func (f *fakeClientConn) UpdateState(s resolver.State) error {
+	f.state = s
+	return nil
+}
+
+// TestTarget_MultipleEndpoints asserts that every resolved endpoint is
+// actually handed to the balancer via a manual resolver, not silently
+// dropped down to a single re-resolved address.
+func TestTarget_MultipleEndpoints(t *testing.T) {
+	endpoints := []string{"10.0.0.1:50051", "10.0.0.2:50051", "10.0.0.3:50051"}
+
+	scheme := target(endpoints)
+	if scheme == endpoints[0] {
+		t.Fatalf("target() returned a single literal endpoint for a multi-endpoint resolve: %q", scheme)
+	}
+
+	b := resolver.Get(schemeOf(t, scheme))
+	if b == nil {
+		t.Fatalf("no resolver.Builder registered for target %q", scheme)
+	}
+
+	cc := &fakeClientConn{}
+	r, err := b.Build(resolver.Target{}, cc, resolver.BuildOptions{})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	defer r.Close()
+
+	if len(cc.state.Addresses) != len(endpoints) {
+		t.Fatalf("got %d addresses, want %d (endpoints dropped): %+v", len(cc.state.Addresses), len(endpoints), cc.state.Addresses)
+	}
+	for i, addr := range cc.state.Addresses {
+		if addr.Addr != endpoints[i] {
+			t.Errorf("address %d = %q, want %q", i, addr.Addr, endpoints[i])
+		}
+	}
+}
+
+// schemeOf strips the "scheme:///" suffix target() appends so it can be
+// looked up again via resolver.Get.
+func schemeOf(t *testing.T, dialTarget string) string {
+	t.Helper()
+	const suffix = ":///"
+	if len(dialTarget) < len(suffix) || dialTarget[len(dialTarget)-len(suffix):] != suffix {
+		t.Fatalf("target %q does not look like a registered scheme", dialTarget)
+	}
+	return dialTarget[:len(dialTarget)-len(suffix)]
+}
+
+func TestFirstMessageFrame_RoundTrip(t *testing.T) {
+	payload := []byte("hello")
+	got, err := firstMessageFrame(frameMessage(payload))
+	if err != nil {
+		t.Fatalf("firstMessageFrame() error = %v", err)
+	}
+	if string(got) != string(payload) {
+		t.Errorf("firstMessageFrame() = %q, want %q", got, payload)
+	}
+}
+
+func TestFirstMessageFrame_SkipsTrailerFrame(t *testing.T) {
+	trailer := []byte{0x80, 0, 0, 0, 2, 'O', 'K'}
+	body := append(trailer, frameMessage([]byte("payload"))...)
+
+	got, err := firstMessageFrame(body)
+	if err != nil {
+		t.Fatalf("firstMessageFrame() error = %v", err)
+	}
+	if string(got) != "payload" {
+		t.Errorf("firstMessageFrame() = %q, want %q", got, "payload")
+	}
+}
+
+func TestFirstMessageFrame_NoMessageFrame(t *testing.T) {
+	trailer := []byte{0x80, 0, 0, 0, 2, 'O', 'K'}
+	if _, err := firstMessageFrame(trailer); err == nil {
+		t.Fatal("expected an error when the body has no message frame, got nil")
+	}
+}
@@ -0,0 +1,309 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/ktr0731/evans/config"
+	"github.com/ktr0731/evans/entity"
+	"github.com/pkg/errors"
+	annotations "google.golang.org/genproto/googleapis/api/annotations"
+)
+
+// httpTranscodingClient drives a REST gateway (e.g. grpc-gateway) over
+// HTTP/1.1+JSON instead of invoking RPCs over a gRPC connection, using the
+// google.api.http option on each method to know how to build the request.
+type httpTranscodingClient struct {
+	baseURL string
+	hc      *http.Client
+	rules   map[string]*annotations.HttpRule
+	methods map[string]*desc.MethodDescriptor
+}
+
+// NewHTTPTranscodingClient builds a client that calls methods found in
+// fds over HTTP/JSON transcoding, per each method's google.api.http
+// annotation.
+func NewHTTPTranscodingClient(baseURL string, fds []*desc.FileDescriptor, tlsCfg *config.TLS) (entity.GRPCClient, error) {
+	opts, err := httpDialOptions(tlsCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &httpTranscodingClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		hc:      &http.Client{Transport: opts},
+		rules:   map[string]*annotations.HttpRule{},
+		methods: map[string]*desc.MethodDescriptor{},
+	}
+
+	for _, fd := range fds {
+		for _, svc := range fd.GetServices() {
+			for _, m := range svc.GetMethods() {
+				fqmn := svc.GetFullyQualifiedName() + "/" + m.GetName()
+				c.methods[fqmn] = m
+				if rule, ok := httpRule(m); ok {
+					c.rules[fqmn] = rule
+				}
+			}
+		}
+	}
+
+	return c, nil
+}
+
+func (c *httpTranscodingClient) ReflectionEnabled() bool { return false }
+
+func (c *httpTranscodingClient) ListServices() ([]entity.Service, []entity.Message, error) {
+	return nil, nil, errors.New("reflection is not supported over HTTP transcoding")
+}
+
+func (c *httpTranscodingClient) Invoke(ctx context.Context, fullyQualifiedMethodName string, req, res interface{}) error {
+	rule, ok := c.rules[fullyQualifiedMethodName]
+	if !ok {
+		return errors.Errorf("method %s has no google.api.http annotation", fullyQualifiedMethodName)
+	}
+
+	msg, ok := req.(*dynamic.Message)
+	if !ok {
+		return errors.Errorf("expected a dynamic request message for %s", fullyQualifiedMethodName)
+	}
+
+	bindings := httpRuleBindings(rule)
+
+	var (
+		verb, path, bodySelector string
+		consumed                 map[string]bool
+		err                      error
+	)
+	// Try each binding (the primary one first, then additional_bindings,
+	// in declaration order) and use the first whose path variables all
+	// resolve against msg -- the same "first match wins" semantics
+	// grpc-gateway uses when a method has more than one HTTP mapping.
+	for _, b := range bindings {
+		var expanded string
+		var fields map[string]bool
+		expanded, fields, err = expandPathTemplate(b.pattern, msg)
+		if err == nil {
+			verb, path, consumed, bodySelector = b.verb, expanded, fields, b.body
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	var query url.Values
+	switch bodySelector {
+	case "":
+		query, err = remainingFieldsAsQuery(msg, consumed)
+		if err != nil {
+			return err
+		}
+	case "*":
+		body, err = msg.MarshalJSON()
+		if err != nil {
+			return err
+		}
+	default:
+		fieldMsg, ferr := msg.TryGetFieldByName(bodySelector)
+		if ferr != nil {
+			return errors.Wrapf(ferr, "body field %q not found", bodySelector)
+		}
+		body, err = marshalBodyField(fieldMsg)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal body field %q", bodySelector)
+		}
+	}
+
+	reqURL := c.baseURL + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, verb, reqURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to build HTTP request")
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpRes, err := c.hc.Do(httpReq)
+	if err != nil {
+		return errors.Wrapf(err, "HTTP request to %s failed", reqURL)
+	}
+	defer httpRes.Body.Close()
+
+	b, err := ioutil.ReadAll(httpRes.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read HTTP response body")
+	}
+	if httpRes.StatusCode >= 400 {
+		return errors.Errorf("HTTP transcoding call failed with status %d: %s", httpRes.StatusCode, b)
+	}
+
+	if dm, ok := res.(*dynamic.Message); ok {
+		return dm.UnmarshalJSON(b)
+	}
+	return json.Unmarshal(b, res)
+}
+
+func (c *httpTranscodingClient) Close(ctx context.Context) error {
+	return nil
+}
+
+// httpDialOptions builds the http.RoundTripper used by the transcoding
+// client's http.Client, applying the same TLS settings used for regular
+// gRPC dialing when tlsCfg.Enabled.
+func httpDialOptions(tlsCfg *config.TLS) (http.RoundTripper, error) {
+	if tlsCfg == nil || !tlsCfg.Enabled {
+		return http.DefaultTransport, nil
+	}
+
+	tlsConf, err := buildTLSConfig(tlsCfg)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Transport{TLSClientConfig: tlsConf}, nil
+}
+
+// httpRule extracts the google.api.http option from a method, if any.
+func httpRule(m *desc.MethodDescriptor) (*annotations.HttpRule, bool) {
+	opts := m.GetMethodOptions()
+	if opts == nil {
+		return nil, false
+	}
+	ext, err := proto.GetExtension(opts, annotations.E_Http)
+	if err != nil {
+		return nil, false
+	}
+	rule, ok := ext.(*annotations.HttpRule)
+	if !ok || rule == nil {
+		return nil, false
+	}
+	return rule, true
+}
+
+// binding is one HTTP mapping for a method: either its primary
+// google.api.http pattern or one of its additional_bindings.
+type binding struct {
+	verb    string
+	pattern string
+	body    string
+}
+
+// httpRuleBindings returns every HTTP mapping for rule: its primary
+// binding first, followed by each entry of additional_bindings in order.
+// grpc-gateway lets a method respond to more than one path/verb
+// combination this way (e.g. a collection GET and an item GET), so
+// Invoke tries them in this order until one's path variables resolve.
+func httpRuleBindings(rule *annotations.HttpRule) []binding {
+	bindings := []binding{ruleBinding(rule)}
+	for _, ab := range rule.GetAdditionalBindings() {
+		bindings = append(bindings, ruleBinding(ab))
+	}
+	return bindings
+}
+
+// ruleBinding extracts the verb, path pattern, and body selector from a
+// single HttpRule (either the primary rule or one additional_bindings
+// entry, which shares the same shape but may not itself carry further
+// nested additional_bindings).
+func ruleBinding(rule *annotations.HttpRule) binding {
+	switch p := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return binding{http.MethodGet, p.Get, rule.GetBody()}
+	case *annotations.HttpRule_Post:
+		return binding{http.MethodPost, p.Post, rule.GetBody()}
+	case *annotations.HttpRule_Put:
+		return binding{http.MethodPut, p.Put, rule.GetBody()}
+	case *annotations.HttpRule_Delete:
+		return binding{http.MethodDelete, p.Delete, rule.GetBody()}
+	case *annotations.HttpRule_Patch:
+		return binding{http.MethodPatch, p.Patch, rule.GetBody()}
+	case *annotations.HttpRule_Custom:
+		return binding{p.Custom.GetKind(), p.Custom.GetPath(), rule.GetBody()}
+	default:
+		return binding{http.MethodPost, "", rule.GetBody()}
+	}
+}
+
+// marshalBodyField JSON-encodes the value of a `body:` selector naming a
+// single field. Message-typed fields use the dynamic message's own
+// protobuf-aware JSON marshaling; scalar fields (string, number, bool,
+// repeated, ...) fall back to encoding/json so a selector like
+// `body: "name"` on a string field doesn't silently send an empty body.
+func marshalBodyField(v interface{}) ([]byte, error) {
+	if dm, ok := v.(*dynamic.Message); ok {
+		return dm.MarshalJSON()
+	}
+	return json.Marshal(v)
+}
+
+var pathTemplateVar = regexp.MustCompile(`\{([a-zA-Z0-9_.]+)(?:=[^}]*)?\}`)
+
+// expandPathTemplate substitutes {field=segments/*}-style path variables
+// in pattern with values read off msg, returning the expanded path and the
+// set of field names it consumed (so they can be excluded from the query
+// string).
+func expandPathTemplate(pattern string, msg *dynamic.Message) (string, map[string]bool, error) {
+	consumed := map[string]bool{}
+
+	var substErr error
+	expanded := pathTemplateVar.ReplaceAllStringFunc(pattern, func(match string) string {
+		name := pathTemplateVar.FindStringSubmatch(match)[1]
+		consumed[name] = true
+
+		v, err := msg.TryGetFieldByName(name)
+		if err != nil {
+			substErr = errors.Wrapf(err, "path field %q not found on request message", name)
+			return match
+		}
+		return url.PathEscape(toQueryString(v))
+	})
+	if substErr != nil {
+		return "", nil, substErr
+	}
+	return expanded, consumed, nil
+}
+
+// remainingFieldsAsQuery marshals every scalar field of msg not in
+// consumed into query string parameters, the transcoding behavior for
+// methods with no body selector (GET/DELETE-style RPCs).
+func remainingFieldsAsQuery(msg *dynamic.Message, consumed map[string]bool) (url.Values, error) {
+	q := url.Values{}
+	for _, fd := range msg.GetMessageDescriptor().GetFields() {
+		if consumed[fd.GetName()] {
+			continue
+		}
+		v := msg.GetField(fd)
+		if v == nil {
+			continue
+		}
+		// A repeated field's value is a []interface{}; grpc-gateway (and
+		// the wider REST convention) expects that as repeated
+		// "field=a&field=b" query parameters, not one Go-syntax literal
+		// like "[a b]" crammed into a single parameter.
+		if fd.IsRepeated() {
+			for _, e := range v.([]interface{}) {
+				q.Add(fd.GetName(), toQueryString(e))
+			}
+			continue
+		}
+		q.Set(fd.GetName(), toQueryString(v))
+	}
+	return q, nil
+}
+
+func toQueryString(v interface{}) string {
+	return fmt.Sprint(v)
+}
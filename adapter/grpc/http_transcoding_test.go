@@ -0,0 +1,191 @@
+package grpc
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/jhump/protoreflect/dynamic"
+	annotations "google.golang.org/genproto/googleapis/api/annotations"
+)
+
+func httpRuleForTest(primaryPath, additionalPath string) *annotations.HttpRule {
+	return &annotations.HttpRule{
+		Pattern: &annotations.HttpRule_Get{Get: primaryPath},
+		AdditionalBindings: []*annotations.HttpRule{
+			{Pattern: &annotations.HttpRule_Get{Get: additionalPath}},
+		},
+	}
+}
+
+const testProto = `
+syntax = "proto3";
+
+message Item {
+  string parent = 1;
+  string name   = 2;
+  int32  count  = 3;
+  repeated string tags = 4;
+}
+`
+
+func testMessage(t *testing.T, fields map[string]interface{}) *dynamic.Message {
+	t.Helper()
+
+	parser := protoparse.Parser{
+		Accessor: func(filename string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(testProto)), nil
+		},
+	}
+	fds, err := parser.ParseFiles("test.proto")
+	if err != nil {
+		t.Fatalf("failed to parse test proto: %v", err)
+	}
+
+	md := fds[0].GetMessageTypes()[0]
+	msg := dynamic.NewMessage(md)
+	for name, v := range fields {
+		if err := msg.TrySetFieldByName(name, v); err != nil {
+			t.Fatalf("failed to set field %q: %v", name, err)
+		}
+	}
+	return msg
+}
+
+func TestExpandPathTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		fields   map[string]interface{}
+		wantPath string
+		wantVars []string
+	}{
+		{
+			name:     "single path variable",
+			pattern:  "/v1/items/{name}",
+			fields:   map[string]interface{}{"name": "widget"},
+			wantPath: "/v1/items/widget",
+			wantVars: []string{"name"},
+		},
+		{
+			name:     "path variable with segment wildcard",
+			pattern:  "/v1/{parent=shelves/*}/items",
+			fields:   map[string]interface{}{"parent": "shelves/1"},
+			wantPath: "/v1/shelves%2F1/items",
+			wantVars: []string{"parent"},
+		},
+		{
+			name:     "multiple path variables",
+			pattern:  "/v1/{parent}/items/{name}",
+			fields:   map[string]interface{}{"parent": "shelves/1", "name": "widget"},
+			wantPath: "/v1/shelves%2F1/items/widget",
+			wantVars: []string{"parent", "name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := testMessage(t, tt.fields)
+			gotPath, consumed, err := expandPathTemplate(tt.pattern, msg)
+			if err != nil {
+				t.Fatalf("expandPathTemplate() error = %v", err)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("path = %q, want %q", gotPath, tt.wantPath)
+			}
+			for _, v := range tt.wantVars {
+				if !consumed[v] {
+					t.Errorf("expected %q to be consumed, consumed = %+v", v, consumed)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandPathTemplate_MissingField(t *testing.T) {
+	msg := testMessage(t, nil)
+	if _, _, err := expandPathTemplate("/v1/items/{does_not_exist}", msg); err == nil {
+		t.Fatal("expected an error for an unresolvable path variable, got nil")
+	}
+}
+
+func TestRemainingFieldsAsQuery(t *testing.T) {
+	msg := testMessage(t, map[string]interface{}{
+		"parent": "shelves/1",
+		"name":   "widget",
+		"count":  int32(3),
+	})
+
+	_, consumed, err := expandPathTemplate("/v1/{parent}/items/{name}", msg)
+	if err != nil {
+		t.Fatalf("expandPathTemplate() error = %v", err)
+	}
+
+	q, err := remainingFieldsAsQuery(msg, consumed)
+	if err != nil {
+		t.Fatalf("remainingFieldsAsQuery() error = %v", err)
+	}
+
+	if got := q.Get("count"); got != "3" {
+		t.Errorf("query count = %q, want %q", got, "3")
+	}
+	if q.Get("parent") != "" || q.Get("name") != "" {
+		t.Errorf("expected path-consumed fields to be excluded from the query, got %v", q)
+	}
+}
+
+func TestRemainingFieldsAsQuery_RepeatedField(t *testing.T) {
+	msg := testMessage(t, map[string]interface{}{
+		"name": "widget",
+		"tags": []interface{}{"a", "b", "c"},
+	})
+
+	_, consumed, err := expandPathTemplate("/v1/items/{name}", msg)
+	if err != nil {
+		t.Fatalf("expandPathTemplate() error = %v", err)
+	}
+
+	q, err := remainingFieldsAsQuery(msg, consumed)
+	if err != nil {
+		t.Fatalf("remainingFieldsAsQuery() error = %v", err)
+	}
+
+	got := q["tags"]
+	want := []string{"a", "b", "c"}
+	if len(got) != len(want) {
+		t.Fatalf("tags = %v, want %v", got, want)
+	}
+	for i, v := range want {
+		if got[i] != v {
+			t.Errorf("tags[%d] = %q, want %q", i, got[i], v)
+		}
+	}
+}
+
+func TestMarshalBodyField_Scalar(t *testing.T) {
+	// A `body: "name"` selector naming a scalar (string) field must be
+	// JSON-encoded, not silently dropped into an empty body.
+	b, err := marshalBodyField("widget")
+	if err != nil {
+		t.Fatalf("marshalBodyField() error = %v", err)
+	}
+	if got, want := string(b), `"widget"`; got != want {
+		t.Errorf("marshalBodyField() = %s, want %s", got, want)
+	}
+}
+
+func TestHTTPRuleBindings_IncludesAdditionalBindings(t *testing.T) {
+	rule := httpRuleForTest("/v1/items", "/v1/shelves/{parent}/items/{name}")
+	bindings := httpRuleBindings(rule)
+
+	if len(bindings) != 2 {
+		t.Fatalf("got %d bindings, want 2 (primary + additional_bindings)", len(bindings))
+	}
+	if bindings[0].pattern != "/v1/items" {
+		t.Errorf("primary binding pattern = %q, want %q", bindings[0].pattern, "/v1/items")
+	}
+	if bindings[1].pattern != "/v1/shelves/{parent}/items/{name}" {
+		t.Errorf("additional binding pattern = %q, want %q", bindings[1].pattern, "/v1/shelves/{parent}/items/{name}")
+	}
+}
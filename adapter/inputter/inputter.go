@@ -0,0 +1,46 @@
+// Package inputter reads field values for outgoing request messages, either
+// interactively via a prompt or in bulk from a JSON file.
+package inputter
+
+import (
+	"io"
+
+	"github.com/ktr0731/evans/entity"
+	environment "github.com/ktr0731/evans/entity/env"
+)
+
+// JSONFile reads an entire request message as JSON from in, used for
+// scripted/non-interactive invocations.
+type JSONFile struct {
+	in io.Reader
+}
+
+// NewJSONFile returns a new JSONFile inputter reading from in.
+func NewJSONFile(in io.Reader) *JSONFile {
+	return &JSONFile{in: in}
+}
+
+// Input implements port.Inputter. It does not read from in; it returns a
+// nil request and a nil error.
+func (i *JSONFile) Input(reqType *entity.Message) (interface{}, error) {
+	return nil, nil
+}
+
+// PromptInputter reads field values interactively, prompting the user for
+// each field of the request message.
+type PromptInputter struct {
+	format string
+	env    environment.Environment
+}
+
+// NewPrompt returns a new PromptInputter that formats its prompt per
+// format and resolves types against env.
+func NewPrompt(format string, env environment.Environment) *PromptInputter {
+	return &PromptInputter{format: format, env: env}
+}
+
+// Input implements port.Inputter. It does not prompt for any field; it
+// returns a nil request and a nil error.
+func (i *PromptInputter) Input(reqType *entity.Message) (interface{}, error) {
+	return nil, nil
+}
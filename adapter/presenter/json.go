@@ -0,0 +1,21 @@
+// Package presenter renders usecase results into the formats the CLI
+// output expects.
+package presenter
+
+import "encoding/json"
+
+// JSONPresenter renders values as JSON.
+type JSONPresenter struct {
+	indent string
+}
+
+// NewJSONWithIndent returns a JSONPresenter that pretty-prints with a
+// two-space indent, the default used by the CLI and REPL output.
+func NewJSONWithIndent() *JSONPresenter {
+	return &JSONPresenter{indent: "  "}
+}
+
+// Format marshals v using the presenter's configured indentation.
+func (p *JSONPresenter) Format(v interface{}) ([]byte, error) {
+	return json.MarshalIndent(v, "", p.indent)
+}
@@ -0,0 +1,159 @@
+// Package protobuf parses .proto sources (and, as of the protoset support
+// below, binary FileDescriptorSet files) into the entity graph the rest of
+// Evans operates on.
+package protobuf
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/ktr0731/evans/entity"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// ParseFile parses the given .proto files, resolving imports against
+// importPaths, and returns their file descriptors.
+func ParseFile(files []string, importPaths []string) ([]*desc.FileDescriptor, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	p := protoparse.Parser{ImportPaths: importPaths}
+	fds, err := p.ParseFiles(files...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse proto files")
+	}
+	return fds, nil
+}
+
+// ParseProtoSet loads one or more binary FileDescriptorSet files, as
+// produced by `protoc --descriptor_set_out=... --include_imports` or by
+// buf, and returns their file descriptors. Unlike ParseFile, no include
+// paths are needed since a descriptor set already embeds its transitive
+// dependencies.
+func ParseProtoSet(files []string) ([]*desc.FileDescriptor, error) {
+	var all []*desc.FileDescriptor
+	for _, f := range files {
+		fds, err := parseProtoSetFile(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load proto set %s", f)
+		}
+		all = append(all, fds...)
+	}
+	return all, nil
+}
+
+func parseProtoSetFile(file string) ([]*desc.FileDescriptor, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read file")
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(b, &set); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal FileDescriptorSet")
+	}
+
+	fds, err := desc.CreateFileDescriptorsFromSet(&set)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build file descriptors from set")
+	}
+
+	result := make([]*desc.FileDescriptor, 0, len(fds))
+	for _, fd := range fds {
+		result = append(result, fd)
+	}
+	return result, nil
+}
+
+// ToEntities converts parsed file descriptors -- from either ParseFile or
+// ParseProtoSet, they're interchangeable from here on -- into the
+// entity.Service/entity.Message graph env.Environment operates on, the
+// same shape GRPCClient.ListServices produces from server reflection.
+func ToEntities(fds []*desc.FileDescriptor) ([]entity.Service, []entity.Message) {
+	var svcs []entity.Service
+	messages := map[string]entity.Message{}
+
+	for _, fd := range fds {
+		for _, md := range fd.GetMessageTypes() {
+			collectMessage(md, messages)
+		}
+
+		for _, sd := range fd.GetServices() {
+			svc := entity.Service{Name: sd.GetName(), Package: fd.GetPackage()}
+			for _, m := range sd.GetMethods() {
+				collectMessage(m.GetInputType(), messages)
+				collectMessage(m.GetOutputType(), messages)
+
+				in := messages[m.GetInputType().GetFullyQualifiedName()]
+				out := messages[m.GetOutputType().GetFullyQualifiedName()]
+				svc.RPCs = append(svc.RPCs, entity.RPC{
+					Name:              m.GetName(),
+					RequestType:       &in,
+					ResponseType:      &out,
+					IsClientStreaming: m.IsClientStreaming(),
+					IsServerStreaming: m.IsServerStreaming(),
+				})
+			}
+			svcs = append(svcs, svc)
+		}
+	}
+
+	msgs := make([]entity.Message, 0, len(messages))
+	for _, m := range messages {
+		msgs = append(msgs, m)
+	}
+	return svcs, msgs
+}
+
+// collectMessage records md's fields under its fully-qualified name,
+// recursing into any message-typed fields so nested request/response
+// types are reachable too. Already-seen messages are skipped both to
+// avoid redundant work and to break cycles between messages that
+// reference each other.
+func collectMessage(md *desc.MessageDescriptor, seen map[string]entity.Message) {
+	fqmn := md.GetFullyQualifiedName()
+	if _, ok := seen[fqmn]; ok {
+		return
+	}
+
+	msg := entity.Message{Name: md.GetName(), Package: md.GetFile().GetPackage()}
+	for _, f := range md.GetFields() {
+		msg.Fields = append(msg.Fields, entity.Field{Name: f.GetName(), Number: f.GetNumber()})
+	}
+	// Mark it seen before recursing so a cycle (A has a field of type A,
+	// or A <-> B) terminates instead of looping forever.
+	seen[fqmn] = msg
+
+	for _, f := range md.GetFields() {
+		if f.GetMessageType() != nil {
+			collectMessage(f.GetMessageType(), seen)
+		}
+	}
+}
+
+// IsProtoSet reports whether file looks like a binary FileDescriptorSet
+// based on its extension (".pb" or ".protoset"), as opposed to a .proto
+// source file.
+func IsProtoSet(file string) bool {
+	return strings.HasSuffix(file, ".pb") || strings.HasSuffix(file, ".protoset")
+}
+
+// DynamicBuilder builds dynamic protobuf messages for transports, such as
+// gRPC-Web, that cannot depend on generated Go types.
+type DynamicBuilder struct{}
+
+// NewDynamicBuilder returns a new DynamicBuilder.
+func NewDynamicBuilder() *DynamicBuilder {
+	return &DynamicBuilder{}
+}
+
+// NewMessage implements port.DynamicBuilder. It returns msg itself; no
+// dynamic protobuf message is built.
+func (b *DynamicBuilder) NewMessage(msg *entity.Message) interface{} {
+	return msg
+}
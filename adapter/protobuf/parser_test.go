@@ -0,0 +1,74 @@
+package protobuf
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+)
+
+const toEntitiesTestProto = `
+syntax = "proto3";
+package greet;
+
+message GreetRequest {
+  string name = 1;
+}
+
+message GreetResponse {
+  string message = 2;
+}
+
+service Greeter {
+  rpc Greet (GreetRequest) returns (GreetResponse);
+}
+`
+
+func parseTestProto(t *testing.T) []*desc.FileDescriptor {
+	t.Helper()
+
+	p := protoparse.Parser{
+		Accessor: func(filename string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(toEntitiesTestProto)), nil
+		},
+	}
+	fds, err := p.ParseFiles("greet.proto")
+	if err != nil {
+		t.Fatalf("failed to parse test proto: %v", err)
+	}
+	return fds
+}
+
+func TestToEntities(t *testing.T) {
+	fds := parseTestProto(t)
+
+	svcs, msgs := ToEntities(fds)
+
+	if len(svcs) != 1 {
+		t.Fatalf("got %d services, want 1", len(svcs))
+	}
+	svc := svcs[0]
+	if svc.Name != "Greeter" || svc.Package != "greet" {
+		t.Errorf("service = %+v, want Name=Greeter Package=greet", svc)
+	}
+	if len(svc.RPCs) != 1 || svc.RPCs[0].Name != "Greet" {
+		t.Fatalf("RPCs = %+v, want a single Greet method", svc.RPCs)
+	}
+	rpc := svc.RPCs[0]
+	if rpc.RequestType == nil || rpc.RequestType.Name != "GreetRequest" {
+		t.Errorf("RequestType = %+v, want GreetRequest", rpc.RequestType)
+	}
+	if rpc.ResponseType == nil || rpc.ResponseType.Name != "GreetResponse" {
+		t.Errorf("ResponseType = %+v, want GreetResponse", rpc.ResponseType)
+	}
+
+	names := map[string]bool{}
+	for _, m := range msgs {
+		names[m.Name] = true
+	}
+	if !names["GreetRequest"] || !names["GreetResponse"] {
+		t.Errorf("messages = %+v, want GreetRequest and GreetResponse", msgs)
+	}
+}
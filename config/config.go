@@ -0,0 +1,143 @@
+// Package config provides the data structures used to configure Evans,
+// both from the TOML config file and from command-line flag overrides.
+package config
+
+// Config is the top-level configuration for Evans.
+type Config struct {
+	Default *Default `toml:"default"`
+	Server  *Server  `toml:"server"`
+	Request *Request `toml:"request"`
+	Input   *Input   `toml:"input"`
+}
+
+// Default holds settings related to proto resolution and the default
+// package/service Evans selects on startup.
+type Default struct {
+	ProtoPath []string `toml:"proto_path"`
+	ProtoFile []string `toml:"proto_file"`
+	// ProtoSet lists binary FileDescriptorSet files (as produced by
+	// `protoc --descriptor_set_out=... --include_imports`, or by buf) to
+	// load instead of parsing ProtoFile from source. When set, ProtoPath
+	// and the .proto source entries of ProtoFile are ignored; ProtoFile
+	// entries with a ".pb" or ".protoset" extension are loaded together
+	// with ProtoSet.
+	ProtoSet []string `toml:"proto_set"`
+	Package  string   `toml:"package"`
+	Service  string   `toml:"service"`
+}
+
+// Server holds the connection details for the gRPC server Evans dials.
+type Server struct {
+	Host       string `toml:"host" default:"127.0.0.1"`
+	Port       string `toml:"port" default:"50051"`
+	Reflection bool   `toml:"reflection" default:"false"`
+
+	// TLS configures whether Evans dials the server over TLS, and
+	// optionally presents a client certificate for mutual TLS.
+	TLS *TLS `toml:"tls"`
+
+	// Resolver selects how Host/Port is turned into one or more dialable
+	// endpoints: "static" (default, use Host/Port as-is), "dns" (SRV
+	// lookup), "consul", or "etcd".
+	Resolver string          `toml:"resolver" default:"static"`
+	DNS      *DNSResolver    `toml:"dns"`
+	Consul   *ConsulResolver `toml:"consul"`
+	Etcd     *EtcdResolver   `toml:"etcd"`
+}
+
+// DNSResolver resolves a server address via a DNS SRV lookup of
+// "_grpc._tcp.<Name>".
+type DNSResolver struct {
+	Name string `toml:"name"`
+}
+
+// ConsulResolver resolves a server address by looking up healthy
+// instances of Service registered with a Consul agent.
+type ConsulResolver struct {
+	Endpoints  []string `toml:"endpoints"`
+	Service    string   `toml:"service"`
+	Datacenter string   `toml:"datacenter"`
+	Tags       []string `toml:"tags"`
+}
+
+// EtcdResolver resolves a server address by looking up instances of
+// Service registered under an etcd key prefix.
+type EtcdResolver struct {
+	Endpoints []string `toml:"endpoints"`
+	Service   string   `toml:"service"`
+}
+
+// TLS describes the transport security settings used when dialing the
+// gRPC server.
+type TLS struct {
+	// Enabled switches the dial from insecure to credentials.NewTLS(...).
+	Enabled bool `toml:"enabled" default:"false"`
+	// CACert is a path to a PEM-encoded CA certificate used to verify the
+	// server's certificate. If empty, the host's root CA set is used.
+	CACert string `toml:"ca_cert"`
+	// ClientCert and ClientKey, when both set, are presented to the server
+	// for mutual TLS.
+	ClientCert string `toml:"client_cert"`
+	ClientKey  string `toml:"client_key"`
+	// ServerNameOverride overrides the server name used to verify the
+	// hostname on the returned certificate, useful when dialing through an
+	// IP address or an SNI-less proxy.
+	ServerNameOverride string `toml:"server_name_override"`
+	// InsecureSkipVerify disables server certificate verification. It
+	// should only be used for local development.
+	InsecureSkipVerify bool `toml:"insecure_skip_verify" default:"false"`
+}
+
+// Request holds settings that influence how Evans invokes RPCs.
+type Request struct {
+	Web    bool     `toml:"web" default:"false"`
+	Header []Header `toml:"header"`
+
+	// Mode selects the RPC transport: "" or "grpc" (default) dials a
+	// regular gRPC connection, "http" drives a grpc-gateway-fronted REST
+	// API using each method's google.api.http annotation.
+	Mode string `toml:"mode"`
+
+	// Interceptors lists, by name, the first-party client interceptors to
+	// chain onto every call: "logger", "retry", "otel", "oauth2". Order
+	// matters; each wraps the next. They are attached only to the plain
+	// gRPC transport, not in web or http mode.
+	Interceptors []string `toml:"interceptors"`
+
+	Logger *LoggerInterceptor `toml:"logger"`
+	Retry  *RetryInterceptor  `toml:"retry"`
+	OAuth2 *OAuth2Interceptor `toml:"oauth2"`
+}
+
+// LoggerInterceptor writes the wire-format request/response of every call
+// as JSON to File, one line per message, for later diffing.
+type LoggerInterceptor struct {
+	File string `toml:"file"`
+}
+
+// RetryInterceptor retries a unary call with exponential backoff when it
+// fails with Unavailable, ResourceExhausted, or one of Codes.
+type RetryInterceptor struct {
+	MaxAttempts    int      `toml:"max_attempts" default:"3"`
+	InitialBackoff string   `toml:"initial_backoff" default:"200ms"`
+	Codes          []string `toml:"codes"`
+}
+
+// OAuth2Interceptor attaches a bearer token, refreshed by running Command
+// or reading File, as metadata on every call.
+type OAuth2Interceptor struct {
+	Command string `toml:"command"`
+	File    string `toml:"file"`
+	Header  string `toml:"header" default:"authorization"`
+}
+
+// Header is a single gRPC metadata entry sent with every request.
+type Header struct {
+	Key string `toml:"key"`
+	Val string `toml:"val"`
+}
+
+// Input holds settings for the interactive prompt.
+type Input struct {
+	PromptFormat string `toml:"prompt_format"`
+}
@@ -0,0 +1,47 @@
+package config
+
+// CLIFlags holds command-line flag values that override the corresponding
+// fields loaded from the TOML config file. Zero values are treated as
+// "not set" and leave the config file's value untouched.
+type CLIFlags struct {
+	TLS        bool
+	CACert     string
+	ClientCert string
+	ClientKey  string
+	ServerName string
+	Web        bool
+	HTTP       bool
+}
+
+// Merge applies non-zero CLIFlags on top of c, giving command-line flags
+// precedence over the config file. It mutates c in place.
+func (c *Config) Merge(f *CLIFlags) {
+	if f == nil {
+		return
+	}
+
+	if c.Server.TLS == nil {
+		c.Server.TLS = &TLS{}
+	}
+	if f.TLS {
+		c.Server.TLS.Enabled = true
+	}
+	if f.CACert != "" {
+		c.Server.TLS.CACert = f.CACert
+	}
+	if f.ClientCert != "" {
+		c.Server.TLS.ClientCert = f.ClientCert
+	}
+	if f.ClientKey != "" {
+		c.Server.TLS.ClientKey = f.ClientKey
+	}
+	if f.ServerName != "" {
+		c.Server.TLS.ServerNameOverride = f.ServerName
+	}
+	if f.Web {
+		c.Request.Web = true
+	}
+	if f.HTTP {
+		c.Request.Mode = "http"
+	}
+}
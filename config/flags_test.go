@@ -0,0 +1,95 @@
+package config
+
+import "testing"
+
+func baseConfig() *Config {
+	return &Config{
+		Default: &Default{},
+		Server: &Server{
+			Host: "127.0.0.1",
+			Port: "50051",
+			TLS:  &TLS{CACert: "/etc/ssl/ca.pem"},
+		},
+		Request: &Request{},
+		Input:   &Input{},
+	}
+}
+
+func TestConfig_Merge(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags *CLIFlags
+		check func(t *testing.T, cfg *Config)
+	}{
+		{
+			name:  "nil flags leave config untouched",
+			flags: nil,
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.TLS.Enabled {
+					t.Errorf("TLS.Enabled = true, want false")
+				}
+				if cfg.Server.TLS.CACert != "/etc/ssl/ca.pem" {
+					t.Errorf("CACert = %q, want unchanged", cfg.Server.TLS.CACert)
+				}
+			},
+		},
+		{
+			name:  "--tls enables TLS without touching existing CACert",
+			flags: &CLIFlags{TLS: true},
+			check: func(t *testing.T, cfg *Config) {
+				if !cfg.Server.TLS.Enabled {
+					t.Errorf("TLS.Enabled = false, want true")
+				}
+				if cfg.Server.TLS.CACert != "/etc/ssl/ca.pem" {
+					t.Errorf("CACert = %q, want unchanged", cfg.Server.TLS.CACert)
+				}
+			},
+		},
+		{
+			name:  "--cacert overrides the config file value",
+			flags: &CLIFlags{CACert: "/tmp/other-ca.pem"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.TLS.CACert != "/tmp/other-ca.pem" {
+					t.Errorf("CACert = %q, want /tmp/other-ca.pem", cfg.Server.TLS.CACert)
+				}
+			},
+		},
+		{
+			name:  "--http sets request.mode to http",
+			flags: &CLIFlags{HTTP: true},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Request.Mode != "http" {
+					t.Errorf("Request.Mode = %q, want http", cfg.Request.Mode)
+				}
+			},
+		},
+		{
+			name:  "--web enables web mode",
+			flags: &CLIFlags{Web: true},
+			check: func(t *testing.T, cfg *Config) {
+				if !cfg.Request.Web {
+					t.Errorf("Request.Web = false, want true")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := baseConfig()
+			cfg.Merge(tt.flags)
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestConfig_Merge_InitializesNilTLS(t *testing.T) {
+	cfg := baseConfig()
+	cfg.Server.TLS = nil
+
+	cfg.Merge(&CLIFlags{TLS: true})
+
+	if cfg.Server.TLS == nil || !cfg.Server.TLS.Enabled {
+		t.Fatalf("expected Merge to initialize Server.TLS and enable it, got %+v", cfg.Server.TLS)
+	}
+}
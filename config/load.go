@@ -0,0 +1,58 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+)
+
+// defaultPromptFormat is used when no config file sets input.prompt_format.
+const defaultPromptFormat = "{{package}}.{{service}}@{{addr}}> "
+
+// New builds the default Config, then overlays it with the TOML config
+// file at the conventional Evans config path, if one exists.
+func New() (*Config, error) {
+	cfg := &Config{
+		Default: &Default{},
+		Server: &Server{
+			Host:     "127.0.0.1",
+			Port:     "50051",
+			Resolver: "static",
+		},
+		Request: &Request{},
+		Input:   &Input{PromptFormat: defaultPromptFormat},
+	}
+
+	path, err := filePath()
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return cfg, nil
+	}
+
+	if _, err := toml.DecodeFile(path, cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to load config file %s", path)
+	}
+	return cfg, nil
+}
+
+// filePath returns the path to the user's Evans config file, or "" if
+// none exists yet.
+func filePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to resolve the user config directory")
+	}
+
+	path := filepath.Join(dir, "evans", "config.toml")
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to stat config file %s", path)
+	}
+	return path, nil
+}
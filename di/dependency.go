@@ -2,11 +2,11 @@ package di
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"sync"
 
 	"github.com/hashicorp/go-multierror"
+	pbdesc "github.com/jhump/protoreflect/desc"
 	"github.com/ktr0731/evans/adapter/grpc"
 	"github.com/ktr0731/evans/adapter/inputter"
 	"github.com/ktr0731/evans/adapter/presenter"
@@ -17,6 +17,7 @@ import (
 	"github.com/ktr0731/evans/usecase/port"
 	shellwords "github.com/mattn/go-shellwords"
 	"github.com/pkg/errors"
+	grpclib "google.golang.org/grpc"
 )
 
 var (
@@ -26,14 +27,7 @@ var (
 
 func initEnv(cfg *config.Config) (rerr error) {
 	envOnce.Do(func() {
-		paths, err := resolveProtoPaths(cfg)
-		if err != nil {
-			rerr = err
-			return
-		}
-
-		files := resolveProtoFiles(cfg)
-		desc, err := protobuf.ParseFile(files, paths)
+		desc, err := Descriptors(cfg)
 		if err != nil {
 			rerr = err
 			return
@@ -59,7 +53,8 @@ func initEnv(cfg *config.Config) (rerr error) {
 			}
 			env = environment.NewFromServices(svcs, msgs, headers)
 		} else {
-			env = environment.New(desc, headers)
+			svcs, msgs := protobuf.ToEntities(desc)
+			env = environment.NewFromServices(svcs, msgs, headers)
 
 			if pkg := cfg.Default.Package; pkg != "" {
 				if err := env.UsePackage(pkg); err != nil {
@@ -86,6 +81,37 @@ func Env(cfg *config.Config) (environment.Environment, error) {
 	return env, nil
 }
 
+var (
+	descriptors     []*pbdesc.FileDescriptor
+	descriptorsOnce sync.Once
+	descriptorsErr  error
+)
+
+// Descriptors parses the proto sources or protoset files configured under
+// cfg.Default into file descriptors, the graph both initEnv and the HTTP
+// transcoding client build on.
+func Descriptors(cfg *config.Config) ([]*pbdesc.FileDescriptor, error) {
+	descriptorsOnce.Do(func() {
+		// A configured ProtoSet makes ProtoPath/ProtoFile irrelevant (see
+		// config.Default.ProtoSet), so resolve it first and skip proto
+		// path resolution entirely in that branch -- a malformed
+		// proto_path entry must not be able to break a protoset-only
+		// config.
+		if protoSet := resolveProtoSetFiles(cfg); len(protoSet) > 0 {
+			descriptors, descriptorsErr = protobuf.ParseProtoSet(protoSet)
+			return
+		}
+
+		paths, err := resolveProtoPaths(cfg)
+		if err != nil {
+			descriptorsErr = err
+			return
+		}
+		descriptors, descriptorsErr = protobuf.ParseFile(resolveProtoFiles(cfg), paths)
+	})
+	return descriptors, descriptorsErr
+}
+
 func resolveProtoPaths(cfg *config.Config) ([]string, error) {
 	paths := make([]string, 0, len(cfg.Default.ProtoPath))
 	encountered := map[string]bool{}
@@ -126,13 +152,28 @@ func resolveProtoPaths(cfg *config.Config) ([]string, error) {
 func resolveProtoFiles(conf *config.Config) []string {
 	files := make([]string, 0, len(conf.Default.ProtoFile))
 	for _, f := range conf.Default.ProtoFile {
-		if f != "" {
+		if f != "" && !protobuf.IsProtoSet(f) {
 			files = append(files, f)
 		}
 	}
 	return files
 }
 
+// resolveProtoSetFiles returns the binary FileDescriptorSet files Evans
+// should load instead of parsing .proto sources: those explicitly listed
+// under cfg.Default.ProtoSet, plus any entry of cfg.Default.ProtoFile that
+// looks like a protoset by extension.
+func resolveProtoSetFiles(conf *config.Config) []string {
+	var sets []string
+	sets = append(sets, conf.Default.ProtoSet...)
+	for _, f := range conf.Default.ProtoFile {
+		if f != "" && protobuf.IsProtoSet(f) {
+			sets = append(sets, f)
+		}
+	}
+	return sets
+}
+
 var (
 	jsonCLIPresenter     *presenter.JSONPresenter
 	jsonCLIPresenterOnce sync.Once
@@ -179,16 +220,45 @@ var (
 func initGRPCClient(cfg *config.Config) error {
 	var err error
 	gRPCClientOnce.Do(func() {
-		addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
-		if cfg.Request.Web {
+		var resolver port.Resolver
+		resolver, err = Resolver(cfg)
+		if err != nil {
+			return
+		}
+
+		var endpoints []string
+		endpoints, err = resolver.Resolve()
+		if err != nil {
+			err = errors.Wrap(err, "failed to resolve server address")
+			return
+		}
+
+		switch {
+		case cfg.Request.Mode == "http":
+			var desc []*pbdesc.FileDescriptor
+			desc, err = Descriptors(cfg)
+			if err != nil {
+				return
+			}
+			scheme := "http"
+			if cfg.Server.TLS != nil && cfg.Server.TLS.Enabled {
+				scheme = "https"
+			}
+			gRPCClient, err = grpc.NewHTTPTranscodingClient(scheme+"://"+endpoints[0], desc, cfg.Server.TLS)
+		case cfg.Request.Web:
 			var b port.DynamicBuilder
 			b, err = DynamicBuilder()
 			if err != nil {
 				return
 			}
-			gRPCClient = grpc.NewWebClient(addr, cfg.Server.Reflection, b)
-		} else {
-			gRPCClient, err = grpc.NewClient(addr, cfg.Server.Reflection)
+			gRPCClient, err = grpc.NewWebClient(endpoints[0], cfg.Server.Reflection, b, cfg.Server.TLS)
+		default:
+			var interceptorOpts []grpclib.DialOption
+			interceptorOpts, err = Interceptors(cfg)
+			if err != nil {
+				return
+			}
+			gRPCClient, err = grpc.NewClient(endpoints, cfg.Server.Reflection, cfg.Server.TLS, interceptorOpts...)
 		}
 	})
 	return err
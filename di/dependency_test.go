@@ -0,0 +1,59 @@
+package di
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ktr0731/evans/config"
+)
+
+func TestResolveProtoSetFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.Config
+		want []string
+	}{
+		{
+			name: "explicit proto_set entries",
+			cfg:  &config.Config{Default: &config.Default{ProtoSet: []string{"a.protoset", "b.protoset"}}},
+			want: []string{"a.protoset", "b.protoset"},
+		},
+		{
+			name: "proto_file entries that look like a protoset by extension",
+			cfg:  &config.Config{Default: &config.Default{ProtoFile: []string{"a.proto", "b.protoset"}}},
+			want: []string{"b.protoset"},
+		},
+		{
+			name: "proto_set and a protoset-looking proto_file combine",
+			cfg: &config.Config{Default: &config.Default{
+				ProtoSet:  []string{"a.protoset"},
+				ProtoFile: []string{"b.pb", "c.proto"},
+			}},
+			want: []string{"a.protoset", "b.pb"},
+		},
+		{
+			name: "no protoset configured",
+			cfg:  &config.Config{Default: &config.Default{ProtoFile: []string{"a.proto"}}},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveProtoSetFiles(tt.cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveProtoSetFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveProtoFiles(t *testing.T) {
+	cfg := &config.Config{Default: &config.Default{ProtoFile: []string{"a.proto", "", "b.protoset"}}}
+
+	got := resolveProtoFiles(cfg)
+	want := []string{"a.proto"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("resolveProtoFiles() = %v, want %v", got, want)
+	}
+}
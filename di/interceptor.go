@@ -0,0 +1,212 @@
+package di
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ktr0731/evans/config"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Interceptors builds the grpc.DialOption chaining every interceptor
+// listed under cfg.Request.Interceptors, in the order given, so users can
+// attach logging/retry/tracing/auth behavior to GRPCClient without Evans
+// itself knowing about any of it.
+func Interceptors(cfg *config.Config) ([]grpclib.DialOption, error) {
+	var unary []grpclib.UnaryClientInterceptor
+	var stream []grpclib.StreamClientInterceptor
+
+	for _, name := range cfg.Request.Interceptors {
+		switch name {
+		case "logger":
+			i, err := newLoggerInterceptor(cfg.Request.Logger)
+			if err != nil {
+				return nil, err
+			}
+			unary = append(unary, i)
+		case "retry":
+			i, err := newRetryInterceptor(cfg.Request.Retry)
+			if err != nil {
+				return nil, err
+			}
+			unary = append(unary, i)
+		case "otel":
+			unary = append(unary, otelgrpc.UnaryClientInterceptor())
+			stream = append(stream, otelgrpc.StreamClientInterceptor())
+		case "oauth2":
+			i, err := newOAuth2Interceptor(cfg.Request.OAuth2)
+			if err != nil {
+				return nil, err
+			}
+			unary = append(unary, i)
+		default:
+			return nil, errors.Errorf("unknown request.interceptors entry: %s", name)
+		}
+	}
+
+	if len(unary) == 0 && len(stream) == 0 {
+		return nil, nil
+	}
+	return []grpclib.DialOption{
+		grpclib.WithChainUnaryInterceptor(unary...),
+		grpclib.WithChainStreamInterceptor(stream...),
+	}, nil
+}
+
+// newLoggerInterceptor returns an interceptor that appends the
+// wire-format request and response of every call, as one JSON object per
+// line, to cfg.File for later diffing.
+func newLoggerInterceptor(cfg *config.LoggerInterceptor) (grpclib.UnaryClientInterceptor, error) {
+	if cfg == nil || cfg.File == "" {
+		return nil, errors.New("request.logger.file must be set to use the \"logger\" interceptor")
+	}
+
+	return func(ctx context.Context, method string, req, res interface{}, cc *grpclib.ClientConn, invoker grpclib.UnaryInvoker, opts ...grpclib.CallOption) error {
+		callErr := invoker(ctx, method, req, res, cc, opts...)
+
+		entry := struct {
+			Method   string      `json:"method"`
+			Request  interface{} `json:"request"`
+			Response interface{} `json:"response,omitempty"`
+			Error    string      `json:"error,omitempty"`
+		}{Method: method, Request: req, Response: res}
+		if callErr != nil {
+			entry.Error = callErr.Error()
+		}
+
+		b, err := json.Marshal(entry)
+		if err == nil {
+			appendLine(cfg.File, b)
+		}
+
+		return callErr
+	}, nil
+}
+
+func appendLine(path string, line []byte) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(line)
+	f.Write([]byte("\n"))
+}
+
+// newRetryInterceptor returns an interceptor that retries a failed unary
+// call with exponential backoff when its status code is Unavailable,
+// ResourceExhausted, or one of cfg.Codes.
+func newRetryInterceptor(cfg *config.RetryInterceptor) (grpclib.UnaryClientInterceptor, error) {
+	if cfg == nil {
+		cfg = &config.RetryInterceptor{}
+	}
+
+	initialBackoff := cfg.InitialBackoff
+	if initialBackoff == "" {
+		initialBackoff = "200ms"
+	}
+	backoff, err := time.ParseDuration(initialBackoff)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid request.retry.initial_backoff: %s", initialBackoff)
+	}
+
+	retryable := map[codes.Code]bool{
+		codes.Unavailable:       true,
+		codes.ResourceExhausted: true,
+	}
+	for _, c := range cfg.Codes {
+		if code, ok := parseCode(c); ok {
+			retryable[code] = true
+		}
+	}
+
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+
+	return func(ctx context.Context, method string, req, res interface{}, cc *grpclib.ClientConn, invoker grpclib.UnaryInvoker, opts ...grpclib.CallOption) error {
+		wait := backoff
+		var lastErr error
+		for attempt := 0; attempt < maxAttempts; attempt++ {
+			if attempt > 0 {
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				wait *= 2
+			}
+
+			lastErr = invoker(ctx, method, req, res, cc, opts...)
+			if lastErr == nil {
+				return nil
+			}
+			if !retryable[status.Code(lastErr)] {
+				return lastErr
+			}
+		}
+		return lastErr
+	}, nil
+}
+
+func parseCode(name string) (codes.Code, bool) {
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if strings.EqualFold(c.String(), name) {
+			return c, true
+		}
+	}
+	return codes.Unknown, false
+}
+
+// newOAuth2Interceptor returns an interceptor that attaches a bearer
+// token, refreshed on every call by running cfg.Command or reading
+// cfg.File, as outgoing metadata under cfg.Header.
+func newOAuth2Interceptor(cfg *config.OAuth2Interceptor) (grpclib.UnaryClientInterceptor, error) {
+	if cfg == nil || (cfg.Command == "" && cfg.File == "") {
+		return nil, errors.New("request.oauth2.command or request.oauth2.file must be set to use the \"oauth2\" interceptor")
+	}
+
+	header := cfg.Header
+	if header == "" {
+		header = "authorization"
+	}
+
+	return func(ctx context.Context, method string, req, res interface{}, cc *grpclib.ClientConn, invoker grpclib.UnaryInvoker, opts ...grpclib.CallOption) error {
+		token, err := fetchToken(cfg)
+		if err != nil {
+			return errors.Wrap(err, "failed to refresh OAuth2 token")
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, header, "Bearer "+token)
+		return invoker(ctx, method, req, res, cc, opts...)
+	}, nil
+}
+
+func fetchToken(cfg *config.OAuth2Interceptor) (string, error) {
+	if cfg.Command != "" {
+		var out bytes.Buffer
+		cmd := exec.Command("sh", "-c", cfg.Command)
+		cmd.Stdout = &out
+		if err := cmd.Run(); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(out.String()), nil
+	}
+
+	b, err := ioutil.ReadFile(cfg.File)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,143 @@
+package di
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ktr0731/evans/config"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		wantCode codes.Code
+		wantOK   bool
+	}{
+		{name: "exact match", in: "Unavailable", wantCode: codes.Unavailable, wantOK: true},
+		{name: "case insensitive", in: "unavailable", wantCode: codes.Unavailable, wantOK: true},
+		{name: "another valid code", in: "DeadlineExceeded", wantCode: codes.DeadlineExceeded, wantOK: true},
+		{name: "unknown name", in: "NotARealCode", wantCode: codes.Unknown, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseCode(tt.in)
+			if ok != tt.wantOK {
+				t.Fatalf("parseCode(%q) ok = %v, want %v", tt.in, ok, tt.wantOK)
+			}
+			if ok && got != tt.wantCode {
+				t.Errorf("parseCode(%q) = %v, want %v", tt.in, got, tt.wantCode)
+			}
+		})
+	}
+}
+
+// invokerStub returns the error at the current call index (clamped to the
+// last entry once exhausted) and records how many times it was called.
+func invokerStub(errs []error) (grpclib.UnaryInvoker, *int) {
+	calls := 0
+	return func(ctx context.Context, method string, req, res interface{}, cc *grpclib.ClientConn, opts ...grpclib.CallOption) error {
+		i := calls
+		if i >= len(errs) {
+			i = len(errs) - 1
+		}
+		calls++
+		return errs[i]
+	}, &calls
+}
+
+// TestNewRetryInterceptor_DefaultsInitialBackoffWhenUnset covers a
+// request.retry table that only sets codes -- cfg is non-nil but
+// cfg.InitialBackoff is still "", which must fall back to the
+// documented 200ms default instead of failing time.ParseDuration("").
+func TestNewRetryInterceptor_DefaultsInitialBackoffWhenUnset(t *testing.T) {
+	if _, err := newRetryInterceptor(&config.RetryInterceptor{Codes: []string{"Unavailable"}}); err != nil {
+		t.Fatalf("newRetryInterceptor() error = %v, want nil (initial_backoff should default to 200ms)", err)
+	}
+}
+
+func TestNewRetryInterceptor_RetriesRetryableCodeUntilSuccess(t *testing.T) {
+	interceptor, err := newRetryInterceptor(&config.RetryInterceptor{MaxAttempts: 3, InitialBackoff: "1ms"})
+	if err != nil {
+		t.Fatalf("newRetryInterceptor() error = %v", err)
+	}
+
+	invoker, calls := invokerStub([]error{
+		status.Error(codes.Unavailable, "down"),
+		status.Error(codes.Unavailable, "still down"),
+		nil,
+	})
+
+	if err := interceptor(context.Background(), "/svc/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor returned error = %v, want nil", err)
+	}
+	if *calls != 3 {
+		t.Errorf("invoker called %d times, want 3", *calls)
+	}
+}
+
+func TestNewRetryInterceptor_DoesNotRetryNonRetryableCode(t *testing.T) {
+	interceptor, err := newRetryInterceptor(&config.RetryInterceptor{MaxAttempts: 3, InitialBackoff: "1ms"})
+	if err != nil {
+		t.Fatalf("newRetryInterceptor() error = %v", err)
+	}
+
+	wantErr := status.Error(codes.InvalidArgument, "bad request")
+	invoker, calls := invokerStub([]error{wantErr})
+
+	gotErr := interceptor(context.Background(), "/svc/Method", nil, nil, nil, invoker)
+	if gotErr != wantErr {
+		t.Errorf("interceptor returned error = %v, want %v", gotErr, wantErr)
+	}
+	if *calls != 1 {
+		t.Errorf("invoker called %d times, want 1 (no retry for a non-retryable code)", *calls)
+	}
+}
+
+func TestNewRetryInterceptor_StopsAtMaxAttempts(t *testing.T) {
+	interceptor, err := newRetryInterceptor(&config.RetryInterceptor{MaxAttempts: 2, InitialBackoff: "1ms"})
+	if err != nil {
+		t.Fatalf("newRetryInterceptor() error = %v", err)
+	}
+
+	invoker, calls := invokerStub([]error{
+		status.Error(codes.Unavailable, "down"),
+		status.Error(codes.Unavailable, "still down"),
+		status.Error(codes.Unavailable, "still down again"),
+	})
+
+	if err := interceptor(context.Background(), "/svc/Method", nil, nil, nil, invoker); err == nil {
+		t.Fatal("expected an error once max_attempts is exhausted, got nil")
+	}
+	if *calls != 2 {
+		t.Errorf("invoker called %d times, want 2 (== max_attempts)", *calls)
+	}
+}
+
+func TestNewRetryInterceptor_BackoffDoubles(t *testing.T) {
+	interceptor, err := newRetryInterceptor(&config.RetryInterceptor{MaxAttempts: 3, InitialBackoff: "5ms"})
+	if err != nil {
+		t.Fatalf("newRetryInterceptor() error = %v", err)
+	}
+
+	invoker, _ := invokerStub([]error{
+		status.Error(codes.Unavailable, "down"),
+		status.Error(codes.Unavailable, "still down"),
+		status.Error(codes.Unavailable, "still down again"),
+	})
+
+	// Three attempts wait 5ms then 10ms between them: 15ms total, doubling
+	// each time rather than retrying at a fixed interval.
+	start := time.Now()
+	_ = interceptor(context.Background(), "/svc/Method", nil, nil, nil, invoker)
+	elapsed := time.Since(start)
+
+	if elapsed < 15*time.Millisecond {
+		t.Errorf("elapsed = %s, want at least 15ms (5ms + 10ms backoff)", elapsed)
+	}
+}
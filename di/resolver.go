@@ -0,0 +1,71 @@
+package di
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/ktr0731/evans/config"
+	"github.com/ktr0731/evans/usecase/port"
+	"github.com/pkg/errors"
+)
+
+// staticResolver resolves to the single configured host:port, the
+// behavior Evans has always had.
+type staticResolver struct {
+	addr string
+}
+
+func (r *staticResolver) Resolve() ([]string, error) {
+	return []string{r.addr}, nil
+}
+
+// dnsSRVResolver resolves a server address via a DNS SRV lookup of
+// "_grpc._tcp.<name>", returning one endpoint per SRV record.
+type dnsSRVResolver struct {
+	name string
+}
+
+func (r *dnsSRVResolver) Resolve() ([]string, error) {
+	_, srvs, err := net.LookupSRV("grpc", "tcp", r.name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve SRV records for %s", r.name)
+	}
+	if len(srvs) == 0 {
+		return nil, errors.Errorf("no SRV records found for %s", r.name)
+	}
+
+	endpoints := make([]string, 0, len(srvs))
+	for _, s := range srvs {
+		endpoints = append(endpoints, fmt.Sprintf("%s:%d", s.Target, s.Port))
+	}
+	return endpoints, nil
+}
+
+// Resolver builds the port.Resolver implied by cfg.Server.Resolver,
+// defaulting to the static host:port resolver used by earlier versions of
+// Evans when Resolver is unset.
+func Resolver(cfg *config.Config) (port.Resolver, error) {
+	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
+
+	switch cfg.Server.Resolver {
+	case "", "static":
+		return &staticResolver{addr: addr}, nil
+	case "dns":
+		if cfg.Server.DNS == nil || cfg.Server.DNS.Name == "" {
+			return nil, errors.New("server.dns.name must be set when server.resolver is \"dns\"")
+		}
+		return &dnsSRVResolver{name: cfg.Server.DNS.Name}, nil
+	case "consul":
+		if cfg.Server.Consul == nil || cfg.Server.Consul.Service == "" {
+			return nil, errors.New("server.consul.service must be set when server.resolver is \"consul\"")
+		}
+		return newConsulResolver(cfg.Server.Consul)
+	case "etcd":
+		if cfg.Server.Etcd == nil || cfg.Server.Etcd.Service == "" {
+			return nil, errors.New("server.etcd.service must be set when server.resolver is \"etcd\"")
+		}
+		return newEtcdResolver(cfg.Server.Etcd)
+	default:
+		return nil, errors.Errorf("unknown server.resolver: %s", cfg.Server.Resolver)
+	}
+}
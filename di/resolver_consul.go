@@ -0,0 +1,66 @@
+package di
+
+import (
+	"fmt"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/ktr0731/evans/config"
+	"github.com/ktr0731/evans/usecase/port"
+	"github.com/pkg/errors"
+)
+
+// consulResolver resolves a server address to the set of currently
+// healthy instances of a service registered with a Consul agent.
+type consulResolver struct {
+	client     *consulapi.Client
+	service    string
+	datacenter string
+	tags       []string
+}
+
+func newConsulResolver(cfg *config.ConsulResolver) (port.Resolver, error) {
+	clientCfg := consulapi.DefaultConfig()
+	if len(cfg.Endpoints) > 0 {
+		clientCfg.Address = cfg.Endpoints[0]
+	}
+	if cfg.Datacenter != "" {
+		clientCfg.Datacenter = cfg.Datacenter
+	}
+
+	client, err := consulapi.NewClient(clientCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build Consul client")
+	}
+
+	return &consulResolver{
+		client:     client,
+		service:    cfg.Service,
+		datacenter: cfg.Datacenter,
+		tags:       cfg.Tags,
+	}, nil
+}
+
+func (r *consulResolver) Resolve() ([]string, error) {
+	var tag string
+	if len(r.tags) > 0 {
+		tag = r.tags[0]
+	}
+
+	entries, _, err := r.client.Health().Service(r.service, tag, true, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to look up service %s in Consul", r.service)
+	}
+	if len(entries) == 0 {
+		return nil, errors.Errorf("no healthy instances of service %s found in Consul", r.service)
+	}
+
+	endpoints := make([]string, 0, len(entries))
+	for _, e := range entries {
+		host := e.Service.Address
+		if host == "" {
+			host = e.Node.Address
+		}
+		endpoints = append(endpoints, fmt.Sprintf("%s:%d", host, e.Service.Port))
+	}
+	return endpoints, nil
+}
@@ -0,0 +1,55 @@
+package di
+
+import (
+	"context"
+	"time"
+
+	"github.com/ktr0731/evans/config"
+	"github.com/ktr0731/evans/usecase/port"
+	"github.com/pkg/errors"
+	clientv3 "go.etcd.io/etcd/clientv3"
+)
+
+// etcdResolver resolves a server address by listing the keys registered
+// under an etcd prefix for a service name, the convention used by
+// grpc-etcd-style service registration.
+type etcdResolver struct {
+	client  *clientv3.Client
+	prefix  string
+	timeout time.Duration
+}
+
+func newEtcdResolver(cfg *config.EtcdResolver) (port.Resolver, error) {
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   cfg.Endpoints,
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build etcd client")
+	}
+
+	return &etcdResolver{
+		client:  client,
+		prefix:  "/services/" + cfg.Service + "/",
+		timeout: 5 * time.Second,
+	}, nil
+}
+
+func (r *etcdResolver) Resolve() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	resp, err := r.client.Get(ctx, r.prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list keys under %s in etcd", r.prefix)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, errors.Errorf("no instances registered under %s in etcd", r.prefix)
+	}
+
+	endpoints := make([]string, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		endpoints = append(endpoints, string(kv.Value))
+	}
+	return endpoints, nil
+}
@@ -0,0 +1,116 @@
+package di
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ktr0731/evans/config"
+)
+
+func baseServerConfig() *config.Config {
+	return &config.Config{Server: &config.Server{Host: "localhost", Port: "50051"}}
+}
+
+func TestResolver_DefaultsToStatic(t *testing.T) {
+	cfg := baseServerConfig()
+
+	r, err := Resolver(cfg)
+	if err != nil {
+		t.Fatalf("Resolver() error = %v", err)
+	}
+	static, ok := r.(*staticResolver)
+	if !ok {
+		t.Fatalf("Resolver() = %T, want *staticResolver", r)
+	}
+	if static.addr != "localhost:50051" {
+		t.Errorf("static.addr = %q, want %q", static.addr, "localhost:50051")
+	}
+}
+
+func TestResolver_ExplicitStatic(t *testing.T) {
+	cfg := baseServerConfig()
+	cfg.Server.Resolver = "static"
+
+	if _, err := Resolver(cfg); err != nil {
+		t.Fatalf("Resolver() error = %v", err)
+	}
+}
+
+func TestResolver_DNS(t *testing.T) {
+	tests := []struct {
+		name    string
+		dns     *config.DNSResolver
+		wantErr string
+	}{
+		{name: "missing dns table", dns: nil, wantErr: "server.dns.name must be set"},
+		{name: "empty name", dns: &config.DNSResolver{Name: ""}, wantErr: "server.dns.name must be set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := baseServerConfig()
+			cfg.Server.Resolver = "dns"
+			cfg.Server.DNS = tt.dns
+
+			_, err := Resolver(cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Resolver() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	cfg := baseServerConfig()
+	cfg.Server.Resolver = "dns"
+	cfg.Server.DNS = &config.DNSResolver{Name: "my-service"}
+	r, err := Resolver(cfg)
+	if err != nil {
+		t.Fatalf("Resolver() error = %v", err)
+	}
+	dr, ok := r.(*dnsSRVResolver)
+	if !ok {
+		t.Fatalf("Resolver() = %T, want *dnsSRVResolver", r)
+	}
+	if dr.name != "my-service" {
+		t.Errorf("dnsSRVResolver.name = %q, want %q", dr.name, "my-service")
+	}
+}
+
+func TestResolver_Consul_RequiresService(t *testing.T) {
+	tests := []*config.ConsulResolver{nil, {Service: ""}}
+
+	for _, consul := range tests {
+		cfg := baseServerConfig()
+		cfg.Server.Resolver = "consul"
+		cfg.Server.Consul = consul
+
+		_, err := Resolver(cfg)
+		if err == nil || !strings.Contains(err.Error(), "server.consul.service must be set") {
+			t.Errorf("Resolver() error = %v, want it to mention server.consul.service", err)
+		}
+	}
+}
+
+func TestResolver_Etcd_RequiresService(t *testing.T) {
+	tests := []*config.EtcdResolver{nil, {Service: ""}}
+
+	for _, etcd := range tests {
+		cfg := baseServerConfig()
+		cfg.Server.Resolver = "etcd"
+		cfg.Server.Etcd = etcd
+
+		_, err := Resolver(cfg)
+		if err == nil || !strings.Contains(err.Error(), "server.etcd.service must be set") {
+			t.Errorf("Resolver() error = %v, want it to mention server.etcd.service", err)
+		}
+	}
+}
+
+func TestResolver_UnknownResolver(t *testing.T) {
+	cfg := baseServerConfig()
+	cfg.Server.Resolver = "carrier-pigeon"
+
+	_, err := Resolver(cfg)
+	if err == nil || !strings.Contains(err.Error(), "unknown server.resolver") {
+		t.Fatalf("Resolver() error = %v, want it to mention the unknown resolver name", err)
+	}
+}
@@ -0,0 +1,33 @@
+package di
+
+import (
+	"context"
+	"os"
+	"os/signal"
+)
+
+// CallContext returns a context that the REPL should pass as
+// port.CallParams.Ctx for an interactive call. The returned cancel func is
+// also wired to SIGINT, so pressing Ctrl-C while a unary call is in
+// flight (or blocked waiting on params.Timeout) aborts it cleanly
+// instead of killing the whole process.
+//
+// NOTE: entity.GRPCClient.Invoke is unary-only; there is no
+// client-streaming send-half or server-streaming recv-drain path yet, so
+// this only cancels a single in-flight unary call.
+func CallContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(sig)
+	}()
+
+	return ctx, cancel
+}
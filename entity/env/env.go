@@ -0,0 +1,87 @@
+// Package env holds the currently selected package/service/message state
+// that the REPL and prompt inputter read and write as the user navigates
+// around the loaded proto definitions.
+package env
+
+import (
+	"sync"
+
+	"github.com/ktr0731/evans/entity"
+	"github.com/pkg/errors"
+)
+
+// Environment is the set of operations the REPL needs against the loaded
+// services and messages.
+type Environment interface {
+	UsePackage(name string) error
+	UseService(name string) error
+
+	// RecordRequest remembers req as the last marshaled request message
+	// sent to the RPC identified by fullyQualifiedMethodName, so a later
+	// "call --repeat" can replay it without re-prompting.
+	RecordRequest(fullyQualifiedMethodName string, req interface{})
+
+	// LastRequest returns the request previously recorded by
+	// RecordRequest for fullyQualifiedMethodName, if any.
+	LastRequest(fullyQualifiedMethodName string) (interface{}, bool)
+}
+
+type environment struct {
+	services []entity.Service
+	messages []entity.Message
+	headers  []entity.Header
+
+	pkg string
+	svc string
+
+	mu          sync.Mutex
+	lastRequest map[string]interface{}
+}
+
+// NewFromServices constructs an Environment directly from services and
+// messages, whether discovered via server reflection or converted from
+// parsed .proto files or protosets.
+func NewFromServices(svcs []entity.Service, msgs []entity.Message, headers []entity.Header) Environment {
+	return &environment{services: svcs, messages: msgs, headers: headers, lastRequest: map[string]interface{}{}}
+}
+
+func (e *environment) UsePackage(name string) error {
+	for _, s := range e.services {
+		if s.Package == name {
+			e.pkg = name
+			return nil
+		}
+	}
+	if len(e.services) == 0 {
+		e.pkg = name
+		return nil
+	}
+	return errors.Errorf("unknown package: %s", name)
+}
+
+func (e *environment) UseService(name string) error {
+	for _, s := range e.services {
+		if s.Name == name {
+			e.svc = name
+			return nil
+		}
+	}
+	if len(e.services) == 0 {
+		e.svc = name
+		return nil
+	}
+	return errors.Errorf("unknown service: %s", name)
+}
+
+func (e *environment) RecordRequest(fullyQualifiedMethodName string, req interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastRequest[fullyQualifiedMethodName] = req
+}
+
+func (e *environment) LastRequest(fullyQualifiedMethodName string) (interface{}, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	req, ok := e.lastRequest[fullyQualifiedMethodName]
+	return req, ok
+}
@@ -0,0 +1,30 @@
+package entity
+
+import "context"
+
+// GRPCClient is the port through which Evans invokes RPCs against a real
+// gRPC server, independent of whether the underlying transport is a plain
+// gRPC connection, gRPC-Web, or HTTP/JSON transcoding.
+type GRPCClient interface {
+	// ReflectionEnabled reports whether this client was constructed with
+	// server reflection enabled.
+	ReflectionEnabled() bool
+
+	// ListServices returns the services and messages discovered via server
+	// reflection. It is only valid when ReflectionEnabled returns true.
+	ListServices() ([]Service, []Message, error)
+
+	// Invoke calls the RPC identified by fullyQualifiedMethodName, sending
+	// req and decoding the response into res.
+	Invoke(ctx context.Context, fullyQualifiedMethodName string, req, res interface{}) error
+
+	// Close releases any resources held by the client, such as the
+	// underlying network connection.
+	Close(ctx context.Context) error
+}
+
+// Header is a single gRPC metadata entry.
+type Header struct {
+	Key string
+	Val string
+}
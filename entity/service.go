@@ -0,0 +1,31 @@
+package entity
+
+// Service is a gRPC service discovered either from parsed .proto sources,
+// a FileDescriptorSet, or server reflection.
+type Service struct {
+	Name    string
+	Package string
+	RPCs    []RPC
+}
+
+// RPC is a single method exposed by a Service.
+type RPC struct {
+	Name              string
+	RequestType       *Message
+	ResponseType      *Message
+	IsClientStreaming bool
+	IsServerStreaming bool
+}
+
+// Message is a protobuf message type.
+type Message struct {
+	Name    string
+	Package string
+	Fields  []Field
+}
+
+// Field is a single field of a Message.
+type Field struct {
+	Name   string
+	Number int32
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/ktr0731/evans/config"
+	"github.com/ktr0731/evans/di"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	var opts options
+	if _, err := flags.NewParser(&opts, flags.Default).ParseArgs(args); err != nil {
+		return 1
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	cfg.Merge(opts.cliFlags())
+
+	if opts.Positional.Host != "" {
+		cfg.Server.Host = opts.Positional.Host
+	}
+	if opts.Positional.Port != "" {
+		cfg.Server.Port = opts.Positional.Port
+	}
+
+	if _, err := di.GRPCClient(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,36 @@
+package main
+
+import "github.com/ktr0731/evans/config"
+
+// options are the flags Evans accepts on the command line, parsed by
+// go-flags in main. Each one overrides the matching config file setting
+// via config.Config.Merge.
+type options struct {
+	TLS        bool   `long:"tls" description:"Dial the gRPC server over TLS"`
+	CACert     string `long:"cacert" description:"Path to a PEM-encoded CA certificate used to verify the server"`
+	Cert       string `long:"cert" description:"Path to a PEM-encoded client certificate, for mutual TLS"`
+	CertKey    string `long:"certkey" description:"Path to the private key matching --cert"`
+	ServerName string `long:"servername" description:"Override the server name used for TLS certificate verification"`
+
+	Web  bool `long:"web" description:"Use gRPC-Web instead of gRPC"`
+	HTTP bool `long:"http" description:"Use HTTP/JSON transcoding (google.api.http annotations) instead of gRPC"`
+
+	Positional struct {
+		Host string `positional-arg-name:"host"`
+		Port string `positional-arg-name:"port"`
+	} `positional-args:"yes"`
+}
+
+// cliFlags converts the parsed flags into the config.CLIFlags overlay
+// config.Config.Merge applies on top of the loaded config file.
+func (o *options) cliFlags() *config.CLIFlags {
+	return &config.CLIFlags{
+		TLS:        o.TLS,
+		CACert:     o.CACert,
+		ClientCert: o.Cert,
+		ClientKey:  o.CertKey,
+		ServerName: o.ServerName,
+		Web:        o.Web,
+		HTTP:       o.HTTP,
+	}
+}
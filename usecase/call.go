@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/ktr0731/evans/entity"
+	"github.com/ktr0731/evans/entity/env"
+	"github.com/ktr0731/evans/usecase/port"
+	"github.com/pkg/errors"
+)
+
+// Call invokes the RPC named by params.RPCName, prompting in for field
+// values unless params.Repeat asks to replay the last request recorded
+// for that RPC. The call is bounded by params.Timeout, if set, and
+// aborted if params.Ctx is cancelled, e.g. by a REPL SIGINT handler.
+func Call(params *port.CallParams, out port.OutputPort, in port.Inputter, client entity.GRPCClient, builder port.DynamicBuilder, e env.Environment) (io.Reader, error) {
+	ctx := params.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if params.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, params.Timeout)
+		defer cancel()
+	}
+
+	req, err := resolveRequest(params, in, e)
+	if err != nil {
+		return nil, err
+	}
+	e.RecordRequest(params.RPCName, req)
+
+	res := builder.NewMessage(nil)
+	if err := client.Invoke(ctx, params.RPCName, req, res); err != nil {
+		if ctx.Err() != nil {
+			return nil, errors.Wrapf(ctx.Err(), "call to %s was cancelled", params.RPCName)
+		}
+		return nil, errors.Wrapf(err, "failed to call %s", params.RPCName)
+	}
+
+	b, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal response")
+	}
+	return bytes.NewReader(b), nil
+}
+
+// resolveRequest returns the request message to send: the replayed last
+// request when params.Repeat is set, or a freshly prompted one otherwise.
+func resolveRequest(params *port.CallParams, in port.Inputter, e env.Environment) (interface{}, error) {
+	if params.Repeat {
+		req, ok := e.LastRequest(params.RPCName)
+		if !ok {
+			return nil, errors.Errorf("no previous request recorded for %s, run the call normally first", params.RPCName)
+		}
+		return req, nil
+	}
+
+	req, err := in.Input(nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read input for %s", params.RPCName)
+	}
+	return req, nil
+}
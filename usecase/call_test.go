@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ktr0731/evans/entity"
+	"github.com/ktr0731/evans/entity/env"
+	"github.com/ktr0731/evans/usecase/port"
+)
+
+type fakeInputter struct {
+	req interface{}
+	err error
+}
+
+func (f *fakeInputter) Input(reqType *entity.Message) (interface{}, error) {
+	return f.req, f.err
+}
+
+type fakeGRPCClient struct {
+	invokeErr error
+	resFn     func(res interface{})
+}
+
+func (f *fakeGRPCClient) ReflectionEnabled() bool { return false }
+
+func (f *fakeGRPCClient) ListServices() ([]entity.Service, []entity.Message, error) {
+	return nil, nil, nil
+}
+
+func (f *fakeGRPCClient) Invoke(ctx context.Context, fqmn string, req, res interface{}) error {
+	if f.invokeErr != nil {
+		return f.invokeErr
+	}
+	if f.resFn != nil {
+		f.resFn(res)
+	}
+	return nil
+}
+
+func (f *fakeGRPCClient) Close(ctx context.Context) error { return nil }
+
+type fakeBuilder struct{}
+
+func (fakeBuilder) NewMessage(msg *entity.Message) interface{} {
+	return &map[string]interface{}{}
+}
+
+func TestResolveRequest_FreshPrompt(t *testing.T) {
+	e := env.NewFromServices(nil, nil, nil)
+	in := &fakeInputter{req: "prompted value"}
+
+	got, err := resolveRequest(&port.CallParams{RPCName: "pkg.Svc/Method"}, in, e)
+	if err != nil {
+		t.Fatalf("resolveRequest() error = %v", err)
+	}
+	if got != "prompted value" {
+		t.Errorf("resolveRequest() = %v, want %q", got, "prompted value")
+	}
+}
+
+func TestResolveRequest_RepeatHit(t *testing.T) {
+	e := env.NewFromServices(nil, nil, nil)
+	e.RecordRequest("pkg.Svc/Method", "previous value")
+	in := &fakeInputter{req: "should not be used"}
+
+	got, err := resolveRequest(&port.CallParams{RPCName: "pkg.Svc/Method", Repeat: true}, in, e)
+	if err != nil {
+		t.Fatalf("resolveRequest() error = %v", err)
+	}
+	if got != "previous value" {
+		t.Errorf("resolveRequest() = %v, want the replayed request %q", got, "previous value")
+	}
+}
+
+func TestResolveRequest_RepeatMiss(t *testing.T) {
+	e := env.NewFromServices(nil, nil, nil)
+	in := &fakeInputter{req: "should not be used"}
+
+	_, err := resolveRequest(&port.CallParams{RPCName: "pkg.Svc/Method", Repeat: true}, in, e)
+	if err == nil {
+		t.Fatal("expected an error when no request was previously recorded, got nil")
+	}
+	if !strings.Contains(err.Error(), "no previous request recorded") {
+		t.Errorf("error = %v, want it to mention no previous request recorded", err)
+	}
+}
+
+func TestEnvironment_RecordAndLastRequest(t *testing.T) {
+	e := env.NewFromServices(nil, nil, nil)
+
+	if _, ok := e.LastRequest("pkg.Svc/Method"); ok {
+		t.Fatal("LastRequest() ok = true before any RecordRequest call")
+	}
+
+	e.RecordRequest("pkg.Svc/Method", "first")
+	got, ok := e.LastRequest("pkg.Svc/Method")
+	if !ok || got != "first" {
+		t.Fatalf("LastRequest() = (%v, %v), want (\"first\", true)", got, ok)
+	}
+
+	e.RecordRequest("pkg.Svc/Method", "second")
+	got, ok = e.LastRequest("pkg.Svc/Method")
+	if !ok || got != "second" {
+		t.Fatalf("LastRequest() = (%v, %v), want (\"second\", true) after a second RecordRequest", got, ok)
+	}
+
+	if _, ok := e.LastRequest("pkg.Svc/Other"); ok {
+		t.Error("LastRequest() ok = true for a method that was never recorded")
+	}
+}
+
+func TestCall_RecordsRequestAndReturnsResponse(t *testing.T) {
+	e := env.NewFromServices(nil, nil, nil)
+	in := &fakeInputter{req: "the request"}
+	client := &fakeGRPCClient{
+		resFn: func(res interface{}) {
+			*(res.(*map[string]interface{})) = map[string]interface{}{"ok": true}
+		},
+	}
+
+	out, err := Call(&port.CallParams{RPCName: "pkg.Svc/Method"}, nil, in, client, fakeBuilder{}, e)
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.NewDecoder(out).Decode(&got); err != nil {
+		t.Fatalf("failed to decode Call() output: %v", err)
+	}
+	if got["ok"] != true {
+		t.Errorf("response = %v, want {\"ok\":true}", got)
+	}
+
+	if req, ok := e.LastRequest("pkg.Svc/Method"); !ok || req != "the request" {
+		t.Errorf("Call() did not record the request: got (%v, %v)", req, ok)
+	}
+}
+
+func TestCall_CancelledContextIsReportedAsCancellation(t *testing.T) {
+	e := env.NewFromServices(nil, nil, nil)
+	in := &fakeInputter{req: "the request"}
+	client := &fakeGRPCClient{invokeErr: errors.New("transport closed")}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Call(&port.CallParams{RPCName: "pkg.Svc/Method", Ctx: ctx}, nil, in, client, fakeBuilder{}, e)
+	if err == nil {
+		t.Fatal("expected an error when Ctx is already cancelled, got nil")
+	}
+	if !strings.Contains(err.Error(), "was cancelled") {
+		t.Errorf("error = %v, want it to mention cancellation", err)
+	}
+}
@@ -0,0 +1,26 @@
+package port
+
+import (
+	"context"
+	"time"
+)
+
+// CallParams is the input for Interactor.Call.
+type CallParams struct {
+	// RPCName is the fully-qualified name of the RPC to invoke, e.g.
+	// "api.v1.Greeter/SayHello".
+	RPCName string
+
+	// Ctx is the base context for the call, cancelled by the REPL's SIGINT
+	// handler to abort an in-flight unary call. If nil, Call uses
+	// context.Background().
+	Ctx context.Context
+
+	// Timeout bounds how long the unary call may run. Zero means no
+	// deadline.
+	Timeout time.Duration
+
+	// Repeat, when true, replays the last marshaled request recorded for
+	// RPCName instead of prompting the inputter for new field values.
+	Repeat bool
+}
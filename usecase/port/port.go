@@ -0,0 +1,50 @@
+// Package port declares the input/output boundaries the usecase layer
+// depends on, following the ports-and-adapters convention used throughout
+// Evans: adapters implement these interfaces, usecases only know the
+// interface.
+package port
+
+import (
+	"io"
+
+	"github.com/ktr0731/evans/entity"
+)
+
+// OutputPort renders usecase results for the user.
+type OutputPort interface {
+	io.Writer
+}
+
+// Inputter reads field values for an outgoing request message.
+type Inputter interface {
+	Input(reqType *entity.Message) (interface{}, error)
+}
+
+// DynamicBuilder builds dynamic protobuf messages for the gRPC-Web client,
+// which cannot rely on generated Go types.
+type DynamicBuilder interface {
+	NewMessage(msg *entity.Message) interface{}
+}
+
+// PackageParams is the input for Interactor.Package.
+type PackageParams struct{}
+
+// ServiceParams is the input for Interactor.Service.
+type ServiceParams struct {
+	Name string
+}
+
+// DescribeParams is the input for Interactor.Describe.
+type DescribeParams struct {
+	Msg string
+}
+
+// ShowParams is the input for Interactor.Show.
+type ShowParams struct {
+	Type string
+}
+
+// HeaderParams is the input for Interactor.Header.
+type HeaderParams struct {
+	Headers []entity.Header
+}
@@ -0,0 +1,10 @@
+package port
+
+// Resolver resolves a logical server target, as configured under
+// server.resolver, into one or more dialable "host:port" endpoints.
+// Static config resolves to exactly one endpoint; DNS SRV, consul, and
+// etcd resolvers may return several, which the gRPC client balances over
+// using its built-in round-robin policy.
+type Resolver interface {
+	Resolve() ([]string, error)
+}